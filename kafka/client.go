@@ -3,12 +3,11 @@ package kafka
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"time"
+	"sync"
 
 	"github.com/Shopify/sarama"
 )
@@ -21,30 +20,68 @@ func (e TopicMissingError) Error() string { return e.msg }
 
 type Client struct {
 	client      sarama.Client
+	admin       sarama.ClusterAdmin
 	kafkaConfig *sarama.Config
 	config      *Config
+
+	pool       *ClientPool
+	poolKey    string
+	release    func()
+	releaseOne sync.Once
 }
 
 type Config struct {
 	BootstrapServers *[]string
 	Timeout          int
 	CACert           *x509.Certificate
+	CACerts          []*x509.Certificate
 	CACertFile       string
+	CACertPEM        string
 	ClientCert       *tls.Certificate
 	ClientCertFile   string
 	ClientCertKey    string
+	ClientCertPEM    string
+	ClientKeyPEM     string
+	TLSMinVersion    string
 	TLSEnabled       bool
 	SkipTLSVerify    bool
 	SASLUsername     string
 	SASLPassword     string
+	SASLMechanism    string
+
+	// Kerberos/GSSAPI, only used when SASLMechanism is "GSSAPI"
+	SASLKerberosServiceName string
+	SASLKerberosRealm       string
+	SASLKerberosKeytabPath  string
+
+	// OAUTHBEARER, only used when SASLMechanism is "OAUTHBEARER"
+	SASLOAuthTokenURL     string
+	SASLOAuthClientID     string
+	SASLOAuthClientSecret string
+	SASLOAuthScopes       []string
 }
 
+const (
+	saslMechanismPlain       = "PLAIN"
+	saslMechanismScramSHA256 = "SCRAM-SHA-256"
+	saslMechanismScramSHA512 = "SCRAM-SHA-512"
+	saslMechanismOAuthBearer = "OAUTHBEARER"
+	saslMechanismGSSAPI      = "GSSAPI"
+)
+
+// Environment variables that can carry mTLS client key material without
+// touching disk, e.g. when it's injected by Vault.
+const (
+	envClientCert = "KAFKA_CLIENT_CERT"
+	envClientKey  = "KAFKA_CLIENT_KEY"
+)
+
 func (c *Config) String() string {
 	return fmt.Sprintf("BootstrapServers: %s\nTimeout: %d,\nTLS: %v,SkipVerify: %v", *c.BootstrapServers, c.Timeout, c.TLSEnabled, c.SkipTLSVerify)
 }
 
 func (c *Config) SASLEnabled() bool {
-	return c.SASLUsername != "" || c.SASLPassword != ""
+	return c.SASLMechanism != "" || c.SASLUsername != "" || c.SASLPassword != ""
 }
 
 func NewClient(config *Config) (*Client, error) {
@@ -60,41 +97,52 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, err
 	}
 
-	c, err := sarama.NewClient(bootstrapServers, kc)
+	c, poolKey, release, err := defaultClientPool.acquire(config, kc)
 	if err != nil {
 		log.Println("[ERROR] Error connecting to kafka")
 		return nil, err
 	}
 
+	admin, err := defaultClientPool.adminFor(poolKey)
+	if err != nil {
+		release()
+		log.Println("[ERROR] Error creating Kafka cluster admin")
+		return nil, err
+	}
+
 	sarama.Logger = log.New(os.Stdout, "[TRACE] [Sarama]", log.LstdFlags)
 	return &Client{
 		client:      c,
+		admin:       admin,
 		config:      config,
 		kafkaConfig: kc,
+		pool:        defaultClientPool,
+		poolKey:     poolKey,
+		release:     release,
 	}, kc.Validate()
 }
 
-func (c *Client) DeleteTopic(t string) error {
-	broker, err := c.client.Controller()
-
-	if err != nil {
-		return err
-	}
+// Close releases this Client's reference on the pooled connection. The
+// underlying sarama.Client/Broker stay open and cached for later callers;
+// they are closed only by the pool's TTL/LRU eviction or by an
+// invalidation, and never while something still references them.
+func (c *Client) Close() {
+	c.releaseOne.Do(func() {
+		if c.release != nil {
+			c.release()
+		}
+	})
+}
 
-	timeout := time.Duration(c.config.Timeout) * time.Second
-	req := &sarama.DeleteTopicsRequest{
-		Topics:  []string{t},
-		Timeout: timeout,
-	}
-	res, err := broker.DeleteTopics(req)
+// InvalidateConnection force-evicts this client's cached pool connection,
+// e.g. after a credential rotation where the caller already knows the old
+// connection is no longer valid and shouldn't wait for the pool's TTL.
+func (c *Client) InvalidateConnection() {
+	c.pool.invalidate(c.config)
+}
 
-	if err == nil {
-		for k, e := range res.TopicErrorCodes {
-			if e != sarama.ErrNoError {
-				return fmt.Errorf("%s : %s", k, e)
-			}
-		}
-	} else {
+func (c *Client) DeleteTopic(t string) error {
+	if err := c.admin.DeleteTopic(t); err != nil {
 		log.Printf("[ERROR] Error deleting topic %s from Kafka: %s", t, err)
 		return err
 	}
@@ -105,100 +153,76 @@ func (c *Client) DeleteTopic(t string) error {
 }
 
 func (c *Client) UpdateTopic(topic Topic) error {
-	broker, err := c.client.Controller()
-
-	if err != nil {
-		return err
-	}
-
-	r := &sarama.AlterConfigsRequest{
-		Resources:    configToResources(topic),
-		ValidateOnly: false,
+	entries := map[string]*string{}
+	for k, v := range topic.Config {
+		entries[k] = v
 	}
 
-	res, err := broker.AlterConfigs(r)
-
-	if err != nil {
+	if err := c.admin.AlterConfig(sarama.TopicResource, topic.Name, entries, false); err != nil {
 		return err
 	}
 
-	if err == nil {
-		for _, e := range res.Resources {
-			if e.ErrorCode != int16(sarama.ErrNoError) {
-				return errors.New(e.ErrorMsg)
-			}
-		}
-	}
-
 	return nil
 }
 
 func (c *Client) CreateTopic(t Topic) error {
-	broker, err := c.client.Controller()
+	detail := &sarama.TopicDetail{
+		NumPartitions:     t.Partitions,
+		ReplicationFactor: t.ReplicationFactor,
+		ConfigEntries:     t.Config,
+	}
 
-	if err != nil {
-		log.Printf("[WARN] Could get an available broker %s", err)
-		return err
+	if len(t.ReplicaAssignment) > 0 {
+		// Kafka requires NumPartitions/ReplicationFactor to be left at -1
+		// when ReplicaAssignment explicitly places every partition.
+		detail.NumPartitions = -1
+		detail.ReplicationFactor = -1
+		detail.ReplicaAssignment = t.ReplicaAssignment
 	}
 
-	timeout := time.Duration(c.config.Timeout) * time.Second
-	log.Printf("[DEBUG] Timeout is %v ", timeout)
-	req := &sarama.CreateTopicsRequest{
-		TopicDetails: map[string]*sarama.TopicDetail{
-			t.Name: {
-				NumPartitions:     t.Partitions,
-				ReplicationFactor: t.ReplicationFactor,
-				ConfigEntries:     t.Config,
-			},
-		},
-		Timeout: timeout,
-	}
-	res, err := broker.CreateTopics(req)
-
-	if err == nil {
-		for _, e := range res.TopicErrors {
-			if e.Err != sarama.ErrNoError {
-				return fmt.Errorf("%s", e.Err)
-			}
-		}
-		log.Printf("[INFO] Created topic %s in Kafka", t.Name)
+	log.Printf("[DEBUG] Creating topic %s with %d partitions", t.Name, t.Partitions)
+	if err := c.admin.CreateTopic(t.Name, detail, false); err != nil {
+		return err
 	}
 
-	return err
+	log.Printf("[INFO] Created topic %s in Kafka", t.Name)
+	return nil
 }
 
 func (c *Client) AddPartitions(t Topic) error {
-	broker, err := c.client.Controller()
-	if err != nil {
-		log.Printf("[ERROR] Unable to fetch controller: %s", err)
-		return err
-	}
-
-	timeout := time.Duration(c.config.Timeout) * time.Second
+	log.Printf("[INFO] Adding partitions to %s in Kafka", t.Name)
 
-	tp := map[string]*sarama.TopicPartition{
-		t.Name: &sarama.TopicPartition{
-			Count: t.Partitions,
-		},
+	var assignment [][]int32
+	if len(t.ReplicaAssignment) > 0 {
+		oldPartitions, err := c.client.Partitions(t.Name)
+		if err != nil {
+			return err
+		}
+		assignment = newPartitionsAssignment(t.ReplicaAssignment, int32(len(oldPartitions)), t.Partitions)
 	}
 
-	req := &sarama.CreatePartitionsRequest{
-		TopicPartitions: tp,
-		Timeout:         timeout,
-		ValidateOnly:    false,
+	if err := c.admin.CreatePartitions(t.Name, t.Partitions, assignment, false); err != nil {
+		return err
 	}
-	log.Printf("[INFO] Adding partitions to %s in Kafka", t.Name)
-	res, err := broker.CreatePartitions(req)
-	if err == nil {
-		for _, e := range res.TopicPartitionErrors {
-			if e.Err != sarama.ErrNoError {
-				return fmt.Errorf("%s", e.Err)
-			}
+
+	log.Printf("[INFO] Added partitions to %s in Kafka", t.Name)
+	return nil
+}
+
+// newPartitionsAssignment converts replicaAssignment, keyed by absolute
+// partition id (see CreateTopic/ReadTopic), into the positional slice
+// sarama.CreatePartitionsRequest.Assignment expects: Assignment[i] is the
+// replica list for partition oldCount+i, not partition i. The broker
+// requires the result to be exactly newTotal-oldCount entries long.
+func newPartitionsAssignment(replicaAssignment map[int32][]int32, oldCount, newTotal int32) [][]int32 {
+	assignment := make([][]int32, newTotal-oldCount)
+	for p, replicas := range replicaAssignment {
+		if p < oldCount {
+			continue
 		}
-		log.Printf("[INFO] Added partitions to %s in Kafka", t.Name)
+		assignment[p-oldCount] = replicas
 	}
-
-	return err
+	return assignment
 }
 
 func (client *Client) ReadTopic(name string) (Topic, error) {
@@ -232,6 +256,13 @@ func (client *Client) ReadTopic(name string) (Topic, error) {
 					topic.ReplicationFactor = int16(r)
 				}
 
+				assignment, err := readReplicaAssignment(c, name, p)
+				if err != nil {
+					log.Printf("[ERROR] Could not read replica assignment for topic %s: %s", t, err)
+					return topic, err
+				}
+				topic.ReplicaAssignment = assignment
+
 				configToSave, err := client.topicConfig(t)
 				if err != nil {
 					log.Printf("[ERROR] Could not get config for topic %s: %s", t, err)
@@ -248,167 +279,85 @@ func (client *Client) ReadTopic(name string) (Topic, error) {
 	return topic, err
 }
 
-func (c *Client) CreateACL(s stringlyTypedACL) error {
-	broker, err := c.availableBroker()
-	if err != nil {
-		return err
-	}
+// readReplicaAssignment reads which brokers currently host each partition's
+// replicas, so `terraform plan` can show drift when an out-of-band
+// reassignment moves replicas around.
+func readReplicaAssignment(c sarama.Client, topic string, partitions []int32) (map[int32][]int32, error) {
+	assignment := map[int32][]int32{}
 
-	ac, err := s.AclCreation()
-	if err != nil {
-		return err
-	}
-	req := &sarama.CreateAclsRequest{
-		Version:      1,
-		AclCreations: []*sarama.AclCreation{ac},
+	for _, p := range partitions {
+		replicas, err := c.Replicas(topic, p)
+		if err != nil {
+			return nil, err
+		}
+		assignment[p] = replicas
 	}
 
-	res, err := broker.CreateAcls(req)
+	return assignment, nil
+}
+
+func (c *Client) CreateACL(s stringlyTypedACL) error {
+	ac, err := s.AclCreation()
 	if err != nil {
 		return err
 	}
 
-	for _, r := range res.AclCreationResponses {
-		if r.Err != sarama.ErrNoError {
-			return r.Err
-		}
-	}
+	return c.admin.CreateACL(ac.Resource, ac.Acl)
+}
 
-	return nil
+// ListACLs describes every ACL on the cluster across all resource types.
+func (c *Client) ListACLs() ([]sarama.ResourceAcls, error) {
+	return c.admin.ListAcls(sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		PermissionType:            sarama.AclPermissionAny,
+		Operation:                 sarama.AclOperationAny,
+	})
 }
 
-func (c *Client) ListACLs() ([]*sarama.ResourceAcls, error) {
-	broker, err := c.availableBroker()
-	if err != nil {
-		return nil, err
-	}
-	err = c.client.RefreshMetadata()
+// FilterACLs describes ACLs matching a single, user-provided filter, unlike
+// ListACLs which queries every resource type with ANY/ANY filters. It lets
+// callers narrow the query to a specific resource pattern type, e.g. only
+// Prefixed ACLs on a given topic.
+func (c *Client) FilterACLs(s stringlyTypedACL) ([]sarama.ResourceAcls, error) {
+	filter, err := s.AclFilter()
 	if err != nil {
 		return nil, err
 	}
-	allResources := []*sarama.DescribeAclsRequest{
-		&sarama.DescribeAclsRequest{
-			Version: 1,
-			AclFilter: sarama.AclFilter{
-				ResourceType:              sarama.AclResourceTopic,
-				ResourcePatternTypeFilter: sarama.AclPatternAny,
-				PermissionType:            sarama.AclPermissionAny,
-				Operation:                 sarama.AclOperationAny,
-			},
-		},
-		&sarama.DescribeAclsRequest{
-			Version: 1,
-			AclFilter: sarama.AclFilter{
-				ResourceType:              sarama.AclResourceGroup,
-				ResourcePatternTypeFilter: sarama.AclPatternAny,
-				PermissionType:            sarama.AclPermissionAny,
-				Operation:                 sarama.AclOperationAny,
-			},
-		},
-		&sarama.DescribeAclsRequest{
-			Version: 1,
-			AclFilter: sarama.AclFilter{
-				ResourceType:              sarama.AclResourceCluster,
-				ResourcePatternTypeFilter: sarama.AclPatternAny,
-				PermissionType:            sarama.AclPermissionAny,
-				Operation:                 sarama.AclOperationAny,
-			},
-		},
-		&sarama.DescribeAclsRequest{
-			Version: 1,
-			AclFilter: sarama.AclFilter{
-				ResourceType:              sarama.AclResourceTransactionalID,
-				ResourcePatternTypeFilter: sarama.AclPatternAny,
-				PermissionType:            sarama.AclPermissionAny,
-				Operation:                 sarama.AclOperationAny,
-			},
-		},
-	}
-	res := []*sarama.ResourceAcls{}
-
-	for _, r := range allResources {
-		aclsR, err := broker.DescribeAcls(r)
-		if err != nil {
-			return nil, err
-		}
 
-		if err == nil {
-			if aclsR.Err != sarama.ErrNoError {
-				return nil, fmt.Errorf("%s", aclsR.Err)
-			}
-		}
-
-		for _, a := range aclsR.ResourceAcls {
-			res = append(res, a)
-		}
-	}
-	return res, err
+	return c.admin.ListAcls(filter)
 }
 
 func (c *Client) topicConfig(topic string) (map[string]*string, error) {
 	conf := map[string]*string{}
-	request := &sarama.DescribeConfigsRequest{
-		Version: 1,
-		Resources: []*sarama.ConfigResource{
-			{
-				Type: sarama.TopicResource,
-				Name: topic,
-			},
-		},
-	}
 
-	broker, err := c.client.Controller()
+	entries, err := c.admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.TopicResource,
+		Name: topic,
+	})
 	if err != nil {
 		return conf, err
 	}
 
-	cr, err := broker.DescribeConfigs(request)
-	if err != nil {
-		return conf, err
-	}
-
-	if len(cr.Resources) > 0 && len(cr.Resources[0].Configs) > 0 {
-		for _, tConf := range cr.Resources[0].Configs {
-			v := tConf.Value
-			log.Printf("[INFO] Topic: %s. %s: %v. Default %v, Source %v, Version %d", topic, tConf.Name, v, tConf.Default, tConf.Source, cr.Version)
-			for _, s := range tConf.Synonyms {
-				log.Printf("[INFO] Syonyms: %v", s)
-			}
+	for _, tConf := range entries {
+		v := tConf.Value
+		log.Printf("[INFO] Topic: %s. %s: %v. Default %v, Source %v", topic, tConf.Name, v, tConf.Default, tConf.Source)
+		for _, s := range tConf.Synonyms {
+			log.Printf("[INFO] Syonyms: %v", s)
+		}
 
-			if isDefault(tConf, int(cr.Version)) {
-				continue
-			}
-			conf[tConf.Name] = &v
+		if isDefault(tConf) {
+			continue
 		}
+		conf[tConf.Name] = &v
 	}
 	return conf, nil
 }
 
-func isDefault(tc *sarama.ConfigEntry, version int) bool {
-	if version == 0 {
-		return tc.Default
-	}
+func isDefault(tc sarama.ConfigEntry) bool {
 	return tc.Source == sarama.SourceDefault || tc.Source == sarama.SourceStaticBroker
 }
 
-func (c *Client) availableBroker() (*sarama.Broker, error) {
-	var err error
-	brokers := *c.config.BootstrapServers
-	kc := c.kafkaConfig
-
-	log.Printf("[DEBUG] Looking for Brokers @ %v", brokers)
-	for _, b := range brokers {
-		broker := sarama.NewBroker(b)
-		err = broker.Open(kc)
-		if err == nil {
-			return broker, nil
-		}
-		log.Printf("[WARN] Broker @ %s cannot be reached\n", b)
-	}
-
-	return nil, fmt.Errorf("No Available Brokers @ %v", brokers)
-}
-
 func (c *Config) newKafkaConfig() (*sarama.Config, error) {
 	kafkaConfig := sarama.NewConfig()
 	kafkaConfig.Version = sarama.V2_0_0_0
@@ -418,6 +367,51 @@ func (c *Config) newKafkaConfig() (*sarama.Config, error) {
 		kafkaConfig.Net.SASL.Enable = true
 		kafkaConfig.Net.SASL.Password = c.SASLPassword
 		kafkaConfig.Net.SASL.User = c.SASLUsername
+
+		mechanism := c.SASLMechanism
+		if mechanism == "" {
+			mechanism = saslMechanismPlain
+		}
+
+		switch mechanism {
+		case saslMechanismPlain:
+			kafkaConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		case saslMechanismScramSHA256:
+			kafkaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+			kafkaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &XDGSCRAMClient{HashGeneratorFcn: SHA256}
+			}
+		case saslMechanismScramSHA512:
+			kafkaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+			kafkaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &XDGSCRAMClient{HashGeneratorFcn: SHA512}
+			}
+		case saslMechanismOAuthBearer:
+			kafkaConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+			kafkaConfig.Net.SASL.TokenProvider = &oauthTokenProvider{
+				tokenURL:     c.SASLOAuthTokenURL,
+				clientID:     c.SASLOAuthClientID,
+				clientSecret: c.SASLOAuthClientSecret,
+				scopes:       c.SASLOAuthScopes,
+			}
+		case saslMechanismGSSAPI:
+			authType := sarama.KRB5_USER_AUTH
+			if c.SASLKerberosKeytabPath != "" {
+				authType = sarama.KRB5_KEYTAB_AUTH
+			}
+
+			kafkaConfig.Net.SASL.Mechanism = sarama.SASLTypeGSSAPI
+			kafkaConfig.Net.SASL.GSSAPI = sarama.GSSAPIConfig{
+				AuthType:    authType,
+				Username:    c.SASLUsername,
+				Password:    c.SASLPassword,
+				ServiceName: c.SASLKerberosServiceName,
+				Realm:       c.SASLKerberosRealm,
+				KeyTabPath:  c.SASLKerberosKeytabPath,
+			}
+		default:
+			return kafkaConfig, fmt.Errorf("Unknown sasl_mechanism: %s", mechanism)
+		}
 	}
 
 	if c.TLSEnabled {
@@ -435,7 +429,9 @@ func (c *Config) newKafkaConfig() (*sarama.Config, error) {
 }
 
 func (c *Config) newTLSConfig() (*tls.Config, error) {
-	tlsConfig := &tls.Config{}
+	tlsConfig := &tls.Config{
+		MinVersion: c.tlsMinVersion(),
+	}
 
 	cert, err := c.clientCert()
 	if err != nil {
@@ -449,19 +445,52 @@ func (c *Config) newTLSConfig() (*tls.Config, error) {
 	if err != nil {
 		return tlsConfig, err
 	}
-	if pool != nil {
-		tlsConfig.RootCAs = pool
-	}
+	tlsConfig.RootCAs = pool
 
 	tlsConfig.BuildNameToCertificate()
 
 	return tlsConfig, nil
 }
 
+func (c *Config) tlsMinVersion() uint16 {
+	switch c.TLSMinVersion {
+	case "1.0":
+		return tls.VersionTLS10
+	case "1.1":
+		return tls.VersionTLS11
+	case "1.3":
+		return tls.VersionTLS13
+	default:
+		return tls.VersionTLS12
+	}
+}
+
+// clientCert resolves the mTLS client certificate/key, preferring an
+// explicit *tls.Certificate, then inline PEM (from config or the
+// KAFKA_CLIENT_CERT/KAFKA_CLIENT_KEY environment variables, so credentials
+// injected by Vault never have to touch disk), then file paths.
 func (c *Config) clientCert() (*tls.Certificate, error) {
 	if c.ClientCert != nil {
 		return c.ClientCert, nil
 	}
+
+	certPEM := c.ClientCertPEM
+	if certPEM == "" {
+		certPEM = os.Getenv(envClientCert)
+	}
+	keyPEM := c.ClientKeyPEM
+	if keyPEM == "" {
+		keyPEM = os.Getenv(envClientKey)
+	}
+
+	if certPEM != "" && keyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	}
+
 	if c.ClientCertFile != "" && c.ClientCertKey != "" {
 		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientCertKey)
 		if err != nil {
@@ -473,16 +502,56 @@ func (c *Config) clientCert() (*tls.Certificate, error) {
 	return nil, nil
 }
 
+// hasExplicitCACert reports whether the config pins trust to specific CA
+// material (a cert, a slice of certs, inline PEM, or a PEM file) rather
+// than relying on the system trust store.
+func (c *Config) hasExplicitCACert() bool {
+	return c.CACert != nil || len(c.CACerts) > 0 || c.CACertPEM != "" || c.CACertFile != ""
+}
+
+// caCertPool builds the pool of CAs used to verify the broker's
+// certificate. When no explicit CA material is configured it starts from
+// the system trust store, so clusters using a publicly-trusted CA need no
+// extra configuration. When a CA is configured, trust is pinned to just
+// that CA material (a single cert, a slice of certs for clusters with
+// multiple issuers, inline PEM, or a PEM file) instead of also trusting
+// every public CA in the system store.
 func (c *Config) caCertPool() (*x509.CertPool, error) {
-	pool := x509.NewCertPool()
+	var pool *x509.CertPool
+
+	if c.hasExplicitCACert() {
+		pool = x509.NewCertPool()
+	} else {
+		var err error
+		pool, err = x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+	}
+
 	if c.CACert != nil {
 		pool.AddCert(c.CACert)
-	} else if c.CACertFile == "" {
+	}
+
+	for _, cert := range c.CACerts {
+		pool.AddCert(cert)
+	}
+
+	if c.CACertPEM != "" {
+		if !pool.AppendCertsFromPEM([]byte(c.CACertPEM)) {
+			return nil, fmt.Errorf("failed to parse ca_cert_pem")
+		}
+	}
+
+	if c.CACertFile != "" {
 		caCert, err := ioutil.ReadFile(c.CACertFile)
 		if err != nil {
 			return nil, err
 		}
-		pool.AppendCertsFromPEM(caCert)
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate(s) from %s", c.CACertFile)
+		}
 	}
+
 	return pool, nil
 }
@@ -0,0 +1,308 @@
+package kafka
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// defaultPoolMaxSize bounds how many distinct broker/credential
+// combinations the pool keeps warm at once.
+const defaultPoolMaxSize = 20
+
+// defaultPoolTTL is how long a pool entry may sit unused before it becomes
+// eligible for eviction.
+const defaultPoolTTL = 10 * time.Minute
+
+// poolEntry is a cached connection plus the bookkeeping needed to know when
+// it's safe to close it.
+type poolEntry struct {
+	client   sarama.Client
+	admin    sarama.ClusterAdmin
+	refCount int
+	lastUsed time.Time
+	// evicted marks an entry that invalidate removed from p.entries while
+	// callers still held a reference to it. release closes it once the
+	// last of those callers lets go, instead of invalidate closing it out
+	// from under them.
+	evicted bool
+}
+
+// ClientPool caches sarama.Client (and the sarama.ClusterAdmin built from
+// it) keyed by a hash of the bootstrap servers, TLS material and SASL
+// credentials used to create them. Without it, every resource re-dialed
+// the controller on every call; for providers managing hundreds of
+// topics/ACLs that leaks TCP connections and repeats SASL handshakes for
+// no reason. Entries are reference counted so in-flight callers keep a
+// connection alive even if it's evicted for being idle past its TTL or
+// superseded by rotated credentials.
+type ClientPool struct {
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+	max     int
+	ttl     time.Duration
+}
+
+// NewClientPool creates a pool holding at most max entries, evicting any
+// entry unused for longer than ttl. A max/ttl of zero falls back to the
+// package defaults.
+func NewClientPool(max int, ttl time.Duration) *ClientPool {
+	if max <= 0 {
+		max = defaultPoolMaxSize
+	}
+	if ttl <= 0 {
+		ttl = defaultPoolTTL
+	}
+	return &ClientPool{
+		entries: map[string]*poolEntry{},
+		max:     max,
+		ttl:     ttl,
+	}
+}
+
+// defaultClientPool is shared by every Client created via NewClient, the
+// same way sarama.Logger is shared process-wide.
+var defaultClientPool = NewClientPool(defaultPoolMaxSize, defaultPoolTTL)
+
+// configHash identifies a connection by everything that affects how it
+// authenticates. Rotating a SASL password, swapping a client cert, or
+// expanding the trusted CA set changes the hash, so the pool naturally
+// dials a fresh connection instead of reusing one built from stale
+// credentials or an out-of-date trust store.
+func configHash(config *Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%v|%s|%s|%s|%v|%v|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%v|%s|%s",
+		*config.BootstrapServers,
+		config.SASLMechanism,
+		config.SASLUsername,
+		config.SASLPassword,
+		config.TLSEnabled,
+		config.SkipTLSVerify,
+		config.CACertFile,
+		config.ClientCertFile,
+		config.ClientCertKey,
+		config.CACertPEM,
+		config.ClientCertPEM,
+		config.ClientKeyPEM,
+		config.TLSMinVersion,
+		config.SASLKerberosServiceName,
+		config.SASLKerberosRealm,
+		config.SASLKerberosKeytabPath,
+		config.SASLOAuthTokenURL,
+		config.SASLOAuthClientID,
+		config.SASLOAuthClientSecret,
+		config.SASLOAuthScopes,
+		os.Getenv(envClientCert),
+		os.Getenv(envClientKey),
+	)
+	if config.ClientCert != nil {
+		for _, der := range config.ClientCert.Certificate {
+			h.Write(der)
+		}
+	}
+	if config.CACert != nil {
+		h.Write(config.CACert.Raw)
+	}
+	for _, cert := range config.CACerts {
+		h.Write(cert.Raw)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// acquire returns the cached client for config, dialing and caching a new
+// one if none exists yet (or if the cached one was built from different
+// credentials). The returned release func must be called when the caller
+// is done with the client.
+func (p *ClientPool) acquire(config *Config, kafkaConfig *sarama.Config) (sarama.Client, string, func(), error) {
+	key := configHash(config)
+
+	p.mu.Lock()
+	if e, ok := p.entries[key]; ok {
+		e.refCount++
+		e.lastUsed = time.Now()
+		refs := e.refCount
+		p.mu.Unlock()
+		log.Printf("[DEBUG] [ClientPool] reusing cached client %s (refs=%d, size=%d)", key[:8], refs, p.size())
+		return e.client, key, p.release(e), nil
+	}
+	p.mu.Unlock()
+
+	c, err := sarama.NewClient(*(config.BootstrapServers), kafkaConfig)
+	if err != nil {
+		return nil, key, nil, err
+	}
+
+	p.mu.Lock()
+	p.evictIdleLocked()
+	p.evictLRULocked()
+	e := &poolEntry{client: c, refCount: 1, lastUsed: time.Now()}
+	p.entries[key] = e
+	log.Printf("[INFO] [ClientPool] cached new client %s (size=%d)", key[:8], len(p.entries))
+	p.mu.Unlock()
+
+	return c, key, p.release(e), nil
+}
+
+// adminFor returns a sarama.ClusterAdmin built from the pool entry's
+// cached client, creating and caching one if this is the first caller to
+// need it. All admin-protocol calls (topics, ACLs, consumer groups,
+// quotas) go through this shared admin handle instead of each resource
+// building its own.
+func (p *ClientPool) adminFor(key string) (sarama.ClusterAdmin, error) {
+	p.mu.Lock()
+	e, ok := p.entries[key]
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("[ClientPool] no cached client for key %s", key[:8])
+	}
+
+	p.mu.Lock()
+	if e.admin != nil {
+		a := e.admin
+		p.mu.Unlock()
+		return a, nil
+	}
+	p.mu.Unlock()
+
+	admin, err := sarama.NewClusterAdminFromClient(e.client)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	e.admin = admin
+	p.mu.Unlock()
+
+	return admin, nil
+}
+
+// releaseRef decrements the entry's reference count and reports whether
+// the caller should close it now. That's only true when the entry was
+// already marked evicted (by invalidate, while this was its last
+// reference) and this release is the one dropping the count to zero.
+// Callers must hold the pool's mutex.
+func (e *poolEntry) releaseRef() (closeNow bool) {
+	if e.refCount > 0 {
+		e.refCount--
+	}
+	return e.evicted && e.refCount == 0
+}
+
+// markInvalidated records that invalidate has removed this entry from the
+// pool. It reports whether the caller may close the entry immediately
+// (true only when nothing still holds a reference to it); otherwise it
+// flags the entry evicted so the last releaseRef closes it instead, and
+// the connection is never pulled out from under an in-flight caller.
+// Callers must hold the pool's mutex.
+func (e *poolEntry) markInvalidated() (closeNow bool) {
+	if e.refCount == 0 {
+		return true
+	}
+	e.evicted = true
+	return false
+}
+
+// release decrements the entry's reference count. Entries past their TTL
+// with no remaining references are closed and dropped the next time
+// acquire runs its eviction sweep. If the entry was invalidated while this
+// caller still held it, release closes it itself once the last reference
+// is gone.
+func (p *ClientPool) release(e *poolEntry) func() {
+	return func() {
+		p.mu.Lock()
+		closeNow := e.releaseRef()
+		p.mu.Unlock()
+
+		if closeNow {
+			closeEntry(e)
+		}
+	}
+}
+
+// invalidate force-evicts the entry for config, e.g. after a secret
+// rotation where the caller already knows the cached connection is no
+// longer valid. Like evictIdleLocked/evictLRULocked, it only closes the
+// connection once no in-flight caller is still using it; a reference held
+// by a concurrent call (the exact fan-out invalidate exists to support) is
+// instead closed by that caller's own release once it lets go, so the
+// connection is never pulled out from under it.
+func (p *ClientPool) invalidate(config *Config) {
+	key := configHash(config)
+	p.mu.Lock()
+	e, ok := p.entries[key]
+	closeNow := false
+	if ok {
+		delete(p.entries, key)
+		closeNow = e.markInvalidated()
+	}
+	p.mu.Unlock()
+
+	if ok {
+		log.Printf("[INFO] [ClientPool] invalidating client %s", key[:8])
+		if closeNow {
+			closeEntry(e)
+		}
+	}
+}
+
+// evictIdleLocked drops entries with no active callers that have been
+// unused for longer than the pool's TTL. Callers must hold p.mu.
+func (p *ClientPool) evictIdleLocked() {
+	now := time.Now()
+	for key, e := range p.entries {
+		if e.refCount == 0 && now.Sub(e.lastUsed) > p.ttl {
+			log.Printf("[INFO] [ClientPool] evicting idle client %s", key[:8])
+			delete(p.entries, key)
+			closeEntry(e)
+		}
+	}
+}
+
+// evictLRULocked drops the least-recently-used, unreferenced entry when the
+// pool is at capacity. Callers must hold p.mu.
+func (p *ClientPool) evictLRULocked() {
+	if len(p.entries) < p.max {
+		return
+	}
+
+	var oldestKey string
+	var oldest time.Time
+	for key, e := range p.entries {
+		if e.refCount > 0 {
+			continue
+		}
+		if oldestKey == "" || e.lastUsed.Before(oldest) {
+			oldestKey = key
+			oldest = e.lastUsed
+		}
+	}
+
+	if oldestKey == "" {
+		log.Printf("[WARN] [ClientPool] pool full (size=%d) and every entry is in use; not evicting", len(p.entries))
+		return
+	}
+
+	log.Printf("[INFO] [ClientPool] evicting LRU client %s to make room", oldestKey[:8])
+	e := p.entries[oldestKey]
+	delete(p.entries, oldestKey)
+	closeEntry(e)
+}
+
+func (p *ClientPool) size() int {
+	return len(p.entries)
+}
+
+func closeEntry(e *poolEntry) {
+	// e.admin was built from e.client via NewClusterAdminFromClient, so
+	// closing e.client below tears both down; closing e.admin as well
+	// would double-close the same connection.
+	if err := e.client.Close(); err != nil {
+		log.Printf("[WARN] [ClientPool] error closing client: %s", err)
+	}
+}
@@ -0,0 +1,70 @@
+package kafka
+
+import "testing"
+
+func Test_configHashStableForIdenticalConfig(t *testing.T) {
+	servers := []string{"broker1:9092", "broker2:9092"}
+	a := &Config{BootstrapServers: &servers, SASLUsername: "user", SASLPassword: "pass"}
+	b := &Config{BootstrapServers: &servers, SASLUsername: "user", SASLPassword: "pass"}
+
+	if configHash(a) != configHash(b) {
+		t.Errorf("identical configs should hash to the same pool key")
+	}
+}
+
+func Test_configHashChangesOnCredentialRotation(t *testing.T) {
+	servers := []string{"broker1:9092"}
+	before := &Config{BootstrapServers: &servers, SASLUsername: "user", SASLPassword: "old-secret"}
+	after := &Config{BootstrapServers: &servers, SASLUsername: "user", SASLPassword: "new-secret"}
+
+	if configHash(before) == configHash(after) {
+		t.Errorf("rotating SASLPassword should change the pool key so the old connection isn't reused")
+	}
+}
+
+// Test_invalidateWhileInUseDoesNotCloseUntilReleased guards against the
+// bug fixed in 608c35e: invalidate used to delete-and-close an entry
+// unconditionally, closing the connection out from under a concurrent
+// caller that still held a reference to it.
+func Test_invalidateWhileInUseDoesNotCloseUntilReleased(t *testing.T) {
+	e := &poolEntry{refCount: 1}
+
+	if closeNow := e.markInvalidated(); closeNow {
+		t.Fatalf("markInvalidated should not report closeNow while a reference is still held")
+	}
+	if !e.evicted {
+		t.Fatalf("markInvalidated should flag the entry evicted when it can't close immediately")
+	}
+
+	// The in-flight caller finishes and releases its reference; only now
+	// should the entry be reported as closeable.
+	if closeNow := e.releaseRef(); !closeNow {
+		t.Fatalf("releaseRef should report closeNow once the last reference on an evicted entry is released")
+	}
+}
+
+// Test_invalidateUnusedClosesImmediately covers the common case: nothing
+// is holding the entry when it's invalidated, so it can close right away
+// without waiting on a release that may never come.
+func Test_invalidateUnusedClosesImmediately(t *testing.T) {
+	e := &poolEntry{refCount: 0}
+
+	if closeNow := e.markInvalidated(); !closeNow {
+		t.Fatalf("markInvalidated should report closeNow for an entry with no active callers")
+	}
+}
+
+// Test_releaseRefDoesNotCloseAnEntryThatWasNeverInvalidated covers the
+// everyday release path (no invalidation involved): dropping to zero
+// references should never trigger a close on its own, since the pool
+// wants to keep the connection warm for the next caller.
+func Test_releaseRefDoesNotCloseAnEntryThatWasNeverInvalidated(t *testing.T) {
+	e := &poolEntry{refCount: 1}
+
+	if closeNow := e.releaseRef(); closeNow {
+		t.Fatalf("releaseRef should not close an entry that was never marked evicted")
+	}
+	if e.refCount != 0 {
+		t.Fatalf("releaseRef should still decrement refCount, got %d", e.refCount)
+	}
+}
@@ -1,11 +1,50 @@
 package kafka
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
 )
 
+// selfSignedCAPEM generates a throwaway self-signed CA certificate for
+// tests that need to exercise PEM/file-loading without a real cluster.
+func selfSignedCAPEM(t *testing.T) ([]byte, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Unix(0, 0),
+		NotAfter:              time.Unix(0, 0).AddDate(10, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), tmpl.Subject.CommonName
+}
+
 func Test_kafkaConfigVersion(t *testing.T) {
 	c := Config{}
 
@@ -18,3 +57,237 @@ func Test_kafkaConfigVersion(t *testing.T) {
 		t.Errorf("Default version should be v1; got %s", cfg.Version)
 	}
 }
+
+func Test_kafkaConfigSASLMechanismDefaultsToPlain(t *testing.T) {
+	c := Config{
+		SASLUsername: "user",
+		SASLPassword: "pass",
+	}
+
+	cfg, err := c.newKafkaConfig()
+	if err != nil {
+		t.Fatalf("Config should be valid: %s", err)
+	}
+
+	if cfg.Net.SASL.Mechanism != sarama.SASLTypePlaintext {
+		t.Errorf("Default SASL mechanism should be PLAIN; got %s", cfg.Net.SASL.Mechanism)
+	}
+}
+
+func Test_kafkaConfigSASLMechanismRejectsUnknown(t *testing.T) {
+	c := Config{
+		SASLUsername:  "user",
+		SASLPassword:  "pass",
+		SASLMechanism: "NOT-A-MECHANISM",
+	}
+
+	if _, err := c.newKafkaConfig(); err == nil {
+		t.Errorf("Unknown SASL mechanism should be rejected")
+	}
+}
+
+func Test_caCertPoolLoadsCACertFile(t *testing.T) {
+	caPEM, _ := selfSignedCAPEM(t)
+
+	f, err := ioutil.TempFile("", "kafka-ca-*.pem")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(caPEM); err != nil {
+		t.Fatalf("failed to write temp file: %s", err)
+	}
+	f.Close()
+
+	c := Config{CACertFile: f.Name()}
+	pool, err := c.caCertPool()
+	if err != nil {
+		t.Fatalf("caCertPool should succeed: %s", err)
+	}
+
+	// The meaningful assertion is that CACertFile actually got merged into
+	// the pool; the pre-fix bug ("else if c.CACertFile == \"\"") meant this
+	// branch was unreachable whenever a CA file was configured.
+	if len(pool.Subjects()) == 0 {
+		t.Errorf("pool should contain at least the configured CA cert, got none")
+	}
+}
+
+func Test_caCertPoolFallsBackToSystemPool(t *testing.T) {
+	c := Config{}
+
+	systemPool, err := x509.SystemCertPool()
+	if err != nil {
+		t.Skipf("no system cert pool available on this platform: %s", err)
+	}
+
+	pool, err := c.caCertPool()
+	if err != nil {
+		t.Fatalf("caCertPool should succeed: %s", err)
+	}
+
+	if len(pool.Subjects()) != len(systemPool.Subjects()) {
+		t.Errorf("with no explicit CA configured, pool should equal the system pool")
+	}
+}
+
+func Test_caCertPoolAcceptsInlinePEM(t *testing.T) {
+	caPEM, _ := selfSignedCAPEM(t)
+
+	c := Config{CACertPEM: string(caPEM)}
+	pool, err := c.caCertPool()
+	if err != nil {
+		t.Fatalf("caCertPool should succeed: %s", err)
+	}
+
+	if len(pool.Subjects()) == 0 {
+		t.Errorf("pool should contain the inline CA cert")
+	}
+}
+
+func Test_caCertPoolPinsTrustWhenExplicitCAConfigured(t *testing.T) {
+	systemPool, err := x509.SystemCertPool()
+	if err != nil {
+		t.Skipf("no system cert pool available on this platform: %s", err)
+	}
+
+	caPEM, _ := selfSignedCAPEM(t)
+
+	c := Config{CACertPEM: string(caPEM)}
+	pool, err := c.caCertPool()
+	if err != nil {
+		t.Fatalf("caCertPool should succeed: %s", err)
+	}
+
+	if len(pool.Subjects()) != 1 {
+		t.Errorf("with an explicit CA configured, pool should be pinned to just it, not also trust the %d system CAs", len(systemPool.Subjects()))
+	}
+}
+
+func Test_clientCertLoadsFromEnv(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).AddDate(10, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	os.Setenv(envClientCert, string(certPEM))
+	os.Setenv(envClientKey, string(keyPEM))
+	defer os.Unsetenv(envClientCert)
+	defer os.Unsetenv(envClientKey)
+
+	c := Config{}
+	cert, err := c.clientCert()
+	if err != nil {
+		t.Fatalf("clientCert should succeed: %s", err)
+	}
+	if cert == nil {
+		t.Fatalf("clientCert should load the cert from KAFKA_CLIENT_CERT/KAFKA_CLIENT_KEY")
+	}
+}
+
+func Test_tlsMinVersionDefaultsTo1_2(t *testing.T) {
+	c := Config{}
+	if got := c.tlsMinVersion(); got != tls.VersionTLS12 {
+		t.Errorf("default TLS min version should be 1.2; got %x", got)
+	}
+}
+
+func Test_aclCreationDefaultsToLiteralPattern(t *testing.T) {
+	s := stringlyTypedACL{
+		ACL: ACL{
+			Principal:      "User:Bob",
+			Host:           "*",
+			Operation:      "Read",
+			PermissionType: "Allow",
+		},
+		Resource: Resource{
+			Type: "Topic",
+			Name: "my-topic",
+		},
+	}
+
+	ac, err := s.AclCreation()
+	if err != nil {
+		t.Fatalf("AclCreation should succeed: %s", err)
+	}
+
+	if ac.Resource.ResoucePatternType != sarama.AclPatternLiteral {
+		t.Errorf("Pattern type should default to Literal; got %v", ac.Resource.ResoucePatternType)
+	}
+}
+
+func Test_aclFilterDefaultsToAnyPattern(t *testing.T) {
+	s := stringlyTypedACL{
+		ACL: ACL{
+			Principal:      "User:Bob",
+			Host:           "*",
+			Operation:      "Read",
+			PermissionType: "Allow",
+		},
+		Resource: Resource{
+			Type: "Topic",
+			Name: "my-topic",
+		},
+	}
+
+	f, err := s.AclFilter()
+	if err != nil {
+		t.Fatalf("AclFilter should succeed: %s", err)
+	}
+
+	if f.ResourcePatternTypeFilter != sarama.AclPatternAny {
+		t.Errorf("Pattern type filter should default to Any; got %v", f.ResourcePatternTypeFilter)
+	}
+}
+
+func Test_newPartitionsAssignmentShiftsToRelativeIndex(t *testing.T) {
+	replicaAssignment := map[int32][]int32{
+		0: {1, 2}, // existing partition; must not appear in the result
+		1: {1, 2}, // existing partition; must not appear in the result
+		2: {3, 4},
+		3: {4, 5},
+	}
+
+	assignment := newPartitionsAssignment(replicaAssignment, 2, 4)
+
+	if len(assignment) != 2 {
+		t.Fatalf("expected 2 new partitions' worth of assignment, got %d", len(assignment))
+	}
+	if got := assignment[0]; !reflect.DeepEqual(got, []int32{3, 4}) {
+		t.Errorf("assignment[0] (partition 2) = %v, want [3 4]", got)
+	}
+	if got := assignment[1]; !reflect.DeepEqual(got, []int32{4, 5}) {
+		t.Errorf("assignment[1] (partition 3) = %v, want [4 5]", got)
+	}
+}
+
+func Test_newPartitionsAssignmentOnFreshTopic(t *testing.T) {
+	replicaAssignment := map[int32][]int32{
+		0: {1, 2},
+		1: {2, 3},
+	}
+
+	assignment := newPartitionsAssignment(replicaAssignment, 0, 2)
+
+	if len(assignment) != 2 {
+		t.Fatalf("expected 2 partitions' worth of assignment, got %d", len(assignment))
+	}
+	if got := assignment[0]; !reflect.DeepEqual(got, []int32{1, 2}) {
+		t.Errorf("assignment[0] = %v, want [1 2]", got)
+	}
+}
@@ -60,9 +60,15 @@ func (s stringlyTypedACL) AclCreation() (*sarama.AclCreation, error) {
 		return acl, fmt.Errorf("Unknown resource type: '%s'", s.Resource.Type)
 	}
 
-	patternType := stringToACLPrefix(s.Resource.PatternTypeFilter)
+	patternTypeFilter := s.Resource.PatternTypeFilter
+	if patternTypeFilter == "" {
+		// Preserve pre-v1 behaviour: ACLs without an explicit pattern type
+		// are always Literal.
+		patternTypeFilter = "literal"
+	}
+	patternType := stringToACLPrefix(patternTypeFilter)
 	if patternType == unknownConversion {
-		return acl, fmt.Errorf("Unknown pattern type filter: '%s'", s.Resource.PatternTypeFilter)
+		return acl, fmt.Errorf("Unknown pattern type filter: '%s'", patternTypeFilter)
 	}
 
 	acl.Acl = sarama.Acl{
@@ -107,33 +113,35 @@ func (s stringlyTypedACL) AclFilter() (sarama.AclFilter, error) {
 	}
 	f.ResourceType = rType
 
+	patternTypeFilter := s.Resource.PatternTypeFilter
+	if patternTypeFilter == "" {
+		patternTypeFilter = "any"
+	}
+	patternType := stringToACLPrefix(patternTypeFilter)
+	if patternType == unknownConversion {
+		return f, fmt.Errorf("Unknown pattern type filter: %s", patternTypeFilter)
+	}
+	f.ResourcePatternTypeFilter = patternType
+
 	return f, nil
 }
 
 func (c *Client) DeleteACL(s stringlyTypedACL) error {
-	broker, err := c.availableBroker()
-	if err != nil {
-		return err
-	}
-
 	filter, err := s.AclFilter()
 	if err != nil {
 		return err
 	}
 
-	req := &sarama.DeleteAclsRequest{
-		Filters: []*sarama.AclFilter{&filter},
-	}
 	log.Printf("[INFO] Deleting ACL %v\n", s)
 
-	res, err := broker.DeleteAcls(req)
+	matching, err := c.admin.DeleteACL(filter, false)
 	if err != nil {
 		return err
 	}
 
-	for _, r := range res.FilterResponses {
-		if r.Err != sarama.ErrNoError {
-			return r.Err
+	for _, m := range matching {
+		if m.Err != sarama.ErrNoError {
+			return m.Err
 		}
 	}
 	return nil
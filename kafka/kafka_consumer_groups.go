@@ -0,0 +1,34 @@
+package kafka
+
+import (
+	"log"
+
+	"github.com/Shopify/sarama"
+)
+
+// ListConsumerGroups returns the name and protocol type of every consumer
+// group known to the cluster.
+func (c *Client) ListConsumerGroups() (map[string]string, error) {
+	return c.admin.ListConsumerGroups()
+}
+
+// DescribeConsumerGroups returns full state (members, protocol, coordinator)
+// for the given consumer groups.
+func (c *Client) DescribeConsumerGroups(groups []string) ([]*sarama.GroupDescription, error) {
+	return c.admin.DescribeConsumerGroups(groups)
+}
+
+// ListConsumerGroupOffsets returns the committed offset for each requested
+// topic/partition of a consumer group. A nil topicPartitions fetches every
+// partition the group has committed offsets for.
+func (c *Client) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	return c.admin.ListConsumerGroupOffsets(group, topicPartitions)
+}
+
+// ResetConsumerGroupOffset pins a consumer group's committed offset for a
+// single topic/partition, e.g. to replay or skip records during a
+// migration.
+func (c *Client) ResetConsumerGroupOffset(group, topic string, partition int32, offset int64) error {
+	log.Printf("[INFO] Resetting offset for group %s, topic %s, partition %d to %d", group, topic, partition, offset)
+	return c.admin.ResetConsumerGroupOffset(group, topic, partition, offset)
+}
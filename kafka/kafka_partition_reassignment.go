@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// SubmitPartitionReassignment submits a new replica placement for topic and
+// starts Kafka reassigning partitions in the background.
+func (c *Client) SubmitPartitionReassignment(topic string, assignment map[int32][]int32) error {
+	log.Printf("[INFO] Submitting partition reassignment for topic %s: %v", topic, assignment)
+	return c.admin.AlterPartitionReassignments(topic, orderedReassignment(assignment))
+}
+
+// orderedReassignment converts assignment, keyed by absolute partition id,
+// into the positional slice sarama.AlterPartitionReassignments expects:
+// ordered[p] is the replica list for partition p. The slice is sized to
+// the highest partition id present rather than the number of map entries,
+// since a reassignment may only cover a non-contiguous-from-zero subset of
+// a topic's partitions (e.g. just partition 4).
+func orderedReassignment(assignment map[int32][]int32) [][]int32 {
+	var maxPartition int32
+	for p := range assignment {
+		if p > maxPartition {
+			maxPartition = p
+		}
+	}
+
+	ordered := make([][]int32, maxPartition+1)
+	for p, replicas := range assignment {
+		ordered[p] = replicas
+	}
+	return ordered
+}
+
+// ListPartitionReassignments returns the in-progress reassignment status for
+// the given partitions of topic, keyed by partition ID. A partition with no
+// reassignment in flight is absent from the result.
+func (c *Client) ListPartitionReassignments(topic string, partitions []int32) (map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	status, err := c.admin.ListPartitionReassignments(topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+	return status[topic], nil
+}
+
+// AwaitPartitionReassignment polls ListPartitionReassignments until every
+// partition has finished reassigning or timeout elapses, logging progress
+// along the way.
+func (c *Client) AwaitPartitionReassignment(topic string, partitions []int32, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		inProgress, err := c.ListPartitionReassignments(topic, partitions)
+		if err != nil {
+			return err
+		}
+
+		if len(inProgress) == 0 {
+			log.Printf("[INFO] Partition reassignment for topic %s complete", topic)
+			return nil
+		}
+
+		log.Printf("[INFO] Waiting on %d partition(s) of topic %s to finish reassigning", len(inProgress), topic)
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for partition reassignment of topic %s to complete: %d partition(s) still in progress", topic, len(inProgress))
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_orderedReassignmentHandlesNonContiguousPartitions(t *testing.T) {
+	assignment := map[int32][]int32{
+		4: {1, 2, 3},
+	}
+
+	ordered := orderedReassignment(assignment)
+
+	if len(ordered) != 5 {
+		t.Fatalf("expected slice sized to the highest partition id + 1, got len %d", len(ordered))
+	}
+	if got := ordered[4]; !reflect.DeepEqual(got, []int32{1, 2, 3}) {
+		t.Errorf("ordered[4] = %v, want [1 2 3]", got)
+	}
+}
+
+func Test_orderedReassignmentHandlesSparseSubset(t *testing.T) {
+	assignment := map[int32][]int32{
+		2: {1, 2},
+		4: {3, 4},
+	}
+
+	ordered := orderedReassignment(assignment)
+
+	if len(ordered) != 5 {
+		t.Fatalf("expected slice sized to the highest partition id + 1, got len %d", len(ordered))
+	}
+	if ordered[0] != nil || ordered[1] != nil || ordered[3] != nil {
+		t.Errorf("partitions not in the assignment should be left nil, got %v", ordered)
+	}
+	if got := ordered[2]; !reflect.DeepEqual(got, []int32{1, 2}) {
+		t.Errorf("ordered[2] = %v, want [1 2]", got)
+	}
+	if got := ordered[4]; !reflect.DeepEqual(got, []int32{3, 4}) {
+		t.Errorf("ordered[4] = %v, want [3 4]", got)
+	}
+}
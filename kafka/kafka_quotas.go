@@ -0,0 +1,20 @@
+package kafka
+
+import (
+	"log"
+
+	"github.com/Shopify/sarama"
+)
+
+// DescribeClientQuotas returns the client/user quota entries matching the
+// given filter components.
+func (c *Client) DescribeClientQuotas(components []sarama.QuotaFilterComponent, strict bool) ([]sarama.DescribeClientQuotasEntry, error) {
+	return c.admin.DescribeClientQuotas(components, strict)
+}
+
+// AlterClientQuotas applies a quota change (e.g. producer/consumer byte
+// rate limits) to the entity identified by entity.
+func (c *Client) AlterClientQuotas(entity []sarama.QuotaEntityComponent, op sarama.ClientQuotaAlteration, validateOnly bool) error {
+	log.Printf("[INFO] Altering client quota for %v", entity)
+	return c.admin.AlterClientQuotas(entity, op, validateOnly)
+}
@@ -0,0 +1,39 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// oauthTokenProvider implements sarama.AccessTokenProvider using the OAuth2
+// client-credentials grant, so SASL/OAUTHBEARER can be used against
+// providers such as MSK IAM/OAuth or a self-hosted OIDC token endpoint.
+type oauthTokenProvider struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+}
+
+func (p *oauthTokenProvider) Token() (*sarama.AccessToken, error) {
+	if p.tokenURL == "" {
+		return nil, fmt.Errorf("sasl_oauth_token_url is required when sasl_mechanism is OAUTHBEARER")
+	}
+
+	cfg := clientcredentials.Config{
+		ClientID:     p.clientID,
+		ClientSecret: p.clientSecret,
+		TokenURL:     p.tokenURL,
+		Scopes:       p.scopes,
+	}
+
+	token, err := cfg.Token(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OAUTHBEARER token: %w", err)
+	}
+
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}